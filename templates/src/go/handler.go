@@ -1,16 +1,22 @@
+//go:build !typed
+
 package main
 
 import (
-    "net/http"
+	"net/http"
 )
 
-// Handler for the "/{{ROUTE}}" endpoint.
+// Handler for the "/{{ROUTE}}" endpoint. This is the plain scaffold
+// generated by default; invok omits this file and stamps
+// handler_typed.go instead when --method/--request/--response are
+// given, but the build tag keeps the two from colliding even if a
+// project ends up with both on disk.
 func {{HANDLER}}(w http.ResponseWriter, r *http.Request) {
-    // You can access query params via r.URL.Query().
-    // For example:
-    // query := r.URL.Query()
-    // name := query.Get("name")
+	// You can access query params via r.URL.Query().
+	// For example:
+	// query := r.URL.Query()
+	// name := query.Get("name")
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Hello World!"))
-}
\ No newline at end of file
+}
@@ -0,0 +1,49 @@
+//go:build typed
+
+package main
+
+import (
+	"net/http"
+
+	"{{MODULE}}/internal/codec"
+)
+
+// {{REQUEST}} is the decoded body of a {{METHOD}} /{{ROUTE}} request.
+type {{REQUEST}} struct {
+}
+
+// {{RESPONSE}} is the JSON-encoded body returned by {{HANDLER}}.
+type {{RESPONSE}} struct {
+}
+
+// {{HANDLER}} handles {{METHOD}} /{{ROUTE}}, decoding a {{REQUEST}} and
+// encoding a {{RESPONSE}}.
+func {{HANDLER}}(w http.ResponseWriter, r *http.Request) {
+	var req {{REQUEST}}
+	if hasRequestBody(r.Method) {
+		if err := codec.DecodeJSON(r, &req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// TODO: validate req and populate resp from your business logic.
+	resp := {{RESPONSE}}{}
+
+	if err := codec.EncodeJSON(w, http.StatusOK, resp); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// hasRequestBody reports whether requests with method conventionally
+// carry a body to decode; GET/HEAD/DELETE requests usually don't, and
+// json.Decode on an empty body returns io.EOF rather than a usable
+// zero value.
+func hasRequestBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,56 @@
+//go:build typed
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test{{HANDLER}}(t *testing.T) {
+	validBody, err := json.Marshal({{REQUEST}}{})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	// GET/HEAD/DELETE handlers never decode the body (see
+	// hasRequestBody), so a malformed body only fails methods that
+	// carry one.
+	malformedStatus := http.StatusOK
+	if hasRequestBody(http.Method{{METHOD}}) {
+		malformedStatus = http.StatusBadRequest
+	}
+
+	tests := []struct {
+		name       string
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "valid request",
+			body:       validBody,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed JSON body",
+			body:       []byte("{not json"),
+			wantStatus: malformedStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.Method{{METHOD}}, "/{{ROUTE}}", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			{{HANDLER}}(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
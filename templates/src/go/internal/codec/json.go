@@ -0,0 +1,21 @@
+package codec
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DecodeJSON decodes r's body as JSON into v and closes the body. It
+// is the codec generated typed handlers use; JSON is the only codec
+// this package provides.
+func DecodeJSON(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// EncodeJSON writes status and v, JSON-encoded, as the response.
+func EncodeJSON(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Check reports whether a dependency (a database ping, a downstream
+// HTTP call, ...) is currently healthy.
+type Check func() error
+
+// Registry collects named checks and exposes them as the /healthz
+// and /readyz handlers.
+type Registry struct {
+	mu       sync.RWMutex
+	checks   map[string]Check
+	draining int32
+}
+
+// NewRegistry returns an empty Registry ready for Register calls.
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]Check)}
+}
+
+// Register adds a named dependency check; it runs on every /readyz
+// request. Registering the same name again replaces the earlier check.
+func (reg *Registry) Register(name string, check Check) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.checks[name] = check
+}
+
+// Drain marks the registry as draining so /readyz starts failing
+// immediately, before the caller's graceful shutdown completes, so a
+// load balancer stops routing new traffic while in-flight requests
+// finish.
+func (reg *Registry) Drain() {
+	atomic.StoreInt32(&reg.draining, 1)
+}
+
+func (reg *Registry) isDraining() bool {
+	return atomic.LoadInt32(&reg.draining) == 1
+}
+
+// LivezHandler reports the process is up and not wedged; it does not
+// run dependency checks.
+func (reg *Registry) LivezHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// ReadyzHandler fails once Drain has been called, or if any
+// registered check currently returns an error.
+func (reg *Registry) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if reg.isDraining() {
+		http.Error(w, "draining", http.StatusServiceUnavailable)
+		return
+	}
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	failures := make(map[string]string)
+	for name, check := range reg.checks {
+		if err := check(); err != nil {
+			failures[name] = err.Error()
+		}
+	}
+
+	if len(failures) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(failures)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/handlers"
+)
+
+// Middleware wraps an http.Handler with additional behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies each middleware in order, so the first one listed is
+// the outermost and sees the request first.
+func Chain(h http.Handler, mw ...Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// Logging writes a combined (Apache-style) access log line for every
+// request to stdout.
+func Logging(next http.Handler) http.Handler {
+	return handlers.CombinedLoggingHandler(os.Stdout, next)
+}
+
+// Recover turns a panic in next into a 500 instead of crashing the
+// server, and logs the recovered value.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("recovered from panic: %v", rec)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+type requestIDKey struct{}
+
+// RequestIDKey is the context key the injected request ID is stored
+// under; handlers can read it with r.Context().Value(RequestIDKey).
+var RequestIDKey requestIDKey
+
+// RequestID injects an X-Request-ID header (generating one if the
+// caller didn't send one) and makes it available on the request
+// context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		ctx := context.WithValue(r.Context(), RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// CORSOptions configures the CORS middleware; a nil *CORSOptions
+// passed to CORS falls back to allowing any origin with GET/POST.
+type CORSOptions struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORS returns a middleware that sets Access-Control-* headers per
+// opts, short-circuiting OPTIONS preflight requests with a 200.
+func CORS(opts *CORSOptions) Middleware {
+	if opts == nil {
+		opts = &CORSOptions{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{http.MethodGet, http.MethodPost},
+			AllowedHeaders: []string{"Content-Type"},
+		}
+	}
+	return handlers.CORS(
+		handlers.AllowedOrigins(opts.AllowedOrigins),
+		handlers.AllowedMethods(opts.AllowedMethods),
+		handlers.AllowedHeaders(opts.AllowedHeaders),
+	)
+}
@@ -0,0 +1,49 @@
+package reload
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// listenFDsStart is the first inherited file descriptor under the
+// systemd socket activation convention this package follows.
+const listenFDsStart = 3
+
+// InheritedListener returns the listener handed down by a parent
+// process via Reexec (LISTEN_FDS=1), or ok==false if none was passed.
+func InheritedListener() (listener net.Listener, ok bool, err error) {
+	n, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if n < 1 {
+		return nil, false, nil
+	}
+
+	file := os.NewFile(uintptr(listenFDsStart), "listener")
+	listener, err = net.FileListener(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("reload: inherit listener: %w", err)
+	}
+	return listener, true, nil
+}
+
+// Reexec hands listener's file descriptor to a freshly started copy
+// of the running binary and returns once the child has been started,
+// so the parent can drain its own in-flight requests and exit while
+// the child accepts new connections on the same socket.
+func Reexec(listener *net.TCPListener) error {
+	file, err := listener.File()
+	if err != nil {
+		return fmt.Errorf("reload: duplicate listener fd: %w", err)
+	}
+	defer file.Close()
+
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{file}
+	cmd.Env = append(os.Environ(), "LISTEN_FDS=1")
+
+	return cmd.Start()
+}
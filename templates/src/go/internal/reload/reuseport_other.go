@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package reload
+
+import (
+	"fmt"
+	"net"
+)
+
+// ListenReusePort reports an error on platforms where SO_REUSEPORT
+// isn't wired up, so --reload degrades to a regular bind there
+// instead of failing to build.
+func ListenReusePort(network, addr string) (net.Listener, error) {
+	return nil, fmt.Errorf("reload: SO_REUSEPORT not supported on this platform")
+}
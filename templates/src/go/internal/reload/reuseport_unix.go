@@ -0,0 +1,29 @@
+//go:build linux || darwin
+
+package reload
+
+import (
+	"context"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ListenReusePort binds addr with SO_REUSEPORT set, so a newly started
+// process can bind the same port before the old one has released it,
+// closing the gap a plain net.Listen/Close redeploy would leave.
+func ListenReusePort(network, addr string) (net.Listener, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+	return lc.Listen(context.Background(), network, addr)
+}
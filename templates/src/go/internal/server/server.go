@@ -0,0 +1,84 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Server wraps an http.Server with a shutdown channel and an in-flight
+// request counter so the generated main can trigger the same graceful
+// shutdown path whether it was asked for by an OS signal or the admin
+// API, and can log how many requests were still draining when it did.
+type Server struct {
+	*http.Server
+
+	// ShutdownChan is pushed into by RequestShutdown (called by the
+	// admin shutdown route); the main goroutine selects on it alongside
+	// its own OS signal channel.
+	ShutdownChan chan struct{}
+
+	// DrainTimeout bounds how long Shutdown waits for in-flight
+	// requests to finish before forcing the connections closed.
+	DrainTimeout time.Duration
+
+	inFlight int64
+}
+
+// New builds a Server with the timeouts the template has always used,
+// wired up with a buffered shutdown channel so a signal can be
+// delivered even if nothing is selecting on it yet.
+func New(addr string, handler http.Handler, drainTimeout time.Duration) *Server {
+	return &Server{
+		Server: &http.Server{
+			Addr:         addr,
+			Handler:      handler,
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  15 * time.Second,
+		},
+		ShutdownChan: make(chan struct{}, 1),
+		DrainTimeout: drainTimeout,
+	}
+}
+
+// TrackInFlight is middleware that keeps the in-flight counter
+// accurate for logging during a drain.
+func (s *Server) TrackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&s.inFlight, 1)
+		defer atomic.AddInt64(&s.inFlight, -1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlight returns the number of requests currently being handled.
+func (s *Server) InFlight() int64 {
+	return atomic.LoadInt64(&s.inFlight)
+}
+
+// RequestShutdown signals the main goroutine to begin a graceful
+// shutdown. It is safe to call more than once.
+func (s *Server) RequestShutdown() {
+	select {
+	case s.ShutdownChan <- struct{}{}:
+	default:
+	}
+}
+
+// AdminShutdownHandler authenticates the request against token and,
+// if it matches, requests the same graceful shutdown an OS signal
+// would trigger.
+func (s *Server) AdminShutdownHandler(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		log.Printf("shutdown requested via admin endpoint (%d requests in flight)\n", s.InFlight())
+		s.RequestShutdown()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
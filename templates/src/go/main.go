@@ -1,45 +1,161 @@
 package main
 
 import (
-    "context"
-    "log"
-    "net"
-    "net/http"
-    "os"
-    "os/signal"
-    "syscall"
-    "time"
-
-    "github.com/gorilla/mux"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/browser"
+
+	"{{MODULE}}/internal/health"
+	"{{MODULE}}/internal/metrics"
+	"{{MODULE}}/internal/middleware"
+	"{{MODULE}}/internal/reload"
+	"{{MODULE}}/internal/server"
 )
 
+// parseDrainTimeout parses the INVOK_DRAIN_TIMEOUT env var as a
+// time.Duration, falling back to def if it's unset or malformed.
+func parseDrainTimeout(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Printf("Invalid INVOK_DRAIN_TIMEOUT %q, using default %s: %v", s, def, err)
+		return def
+	}
+	return d
+}
+
+// envOrDefault returns os.Getenv(key) if set, otherwise def.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// namedMiddleware maps the names accepted by --middleware/
+// INVOK_MIDDLEWARE to the middleware they wire in.
+var namedMiddleware = map[string]middleware.Middleware{
+	"log":       middleware.Logging,
+	"recover":   middleware.Recover,
+	"cors":      middleware.CORS(nil),
+	"requestid": middleware.RequestID,
+}
+
+// selectMiddleware parses a comma-separated --middleware value into
+// the ordered list of middleware to wire in, logging and skipping any
+// name that isn't in namedMiddleware.
+func selectMiddleware(names string) []middleware.Middleware {
+	var selected []middleware.Middleware
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		mw, ok := namedMiddleware[name]
+		if !ok {
+			log.Printf("Unknown middleware %q, skipping", name)
+			continue
+		}
+		selected = append(selected, mw)
+	}
+	return selected
+}
+
 func main() {
-    // 1. Use environment variable or a default for the server port.
-    port := os.Getenv("PORT")
-    if port == "" {
-        port = "8080"
-    }
-
-    // 2. Create a new router.
-    r := mux.NewRouter()
-
-    // 3. Register endpoints.
-    // Register the "/{{ROUTE}}" endpoint with the {{HANDLER}}.
-	r.HandleFunc("/{{ROUTE}}", {{HANDLER}})
-
-    // 4. Create an HTTP server with timeouts & the router.
-    srv := &http.Server{
-        Addr:         ":" + port,
-        Handler:      r,
-        ReadTimeout:  5 * time.Second,  // protect against slowloris
-        WriteTimeout: 10 * time.Second, // overall request timeout
-        IdleTimeout:  15 * time.Second, // keep-alive time
-    }
-
-    // 5. Create a net.Listener to have more control over incoming connections.
-	listener, err := net.Listen("tcp", ":"+port)
+	// 1. Use environment variable or a default for the server port.
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	// 1a. --open / INVOK_OPEN=1 launches the default browser once the
+	// listener is ready, for a one-command edit-run-see loop.
+	open := flag.Bool("open", os.Getenv("INVOK_OPEN") == "1", "open the default browser once the server is ready")
+	reloadMode := flag.Bool("reload", os.Getenv("INVOK_RELOAD") == "1", "bind with SO_REUSEPORT and hand off the listener to a re-exec'd process on SIGHUP")
+	drainTimeout := flag.Duration("drain-timeout", parseDrainTimeout(os.Getenv("INVOK_DRAIN_TIMEOUT"), 5*time.Second), "how long to wait for in-flight requests to finish during a graceful shutdown")
+	middlewareList := flag.String("middleware", envOrDefault("INVOK_MIDDLEWARE", "log,recover,cors,requestid"), "comma-separated middleware to wire in, chosen from log,recover,cors,requestid")
+	flag.Parse()
+
+	// 2. Create a new router.
+	r := mux.NewRouter()
+
+	// 3. Register the "/{{ROUTE}}" endpoint. registerRoute is build-
+	// tag gated: the typed scaffold chains .Methods(http.Method{{METHOD}})
+	// onto the same HandleFunc call the plain scaffold leaves unscoped.
+	registerRoute(r)
+
+	// 3a. Register liveness/readiness probes and the Prometheus
+	// scrape endpoint. Register your own dependency checks (DB pings,
+	// downstream calls) on healthReg before serving traffic.
+	healthReg := health.NewRegistry()
+	r.HandleFunc("/healthz", healthReg.LivezHandler)
+	r.HandleFunc("/readyz", healthReg.ReadyzHandler)
+	r.Handle("/metrics", metrics.Handler())
+
+	// 4. Wrap the router in a server.Server that owns the shutdown
+	// channel, the in-flight counter, and the drain timeout, then
+	// register the admin shutdown route so it shares the same path.
+	srv := server.New(":"+port, r, *drainTimeout)
+	adminToken := os.Getenv("INVOK_ADMIN_TOKEN")
+	r.HandleFunc("/admin/shutdown", srv.AdminShutdownHandler(adminToken)).Methods(http.MethodPost)
+
+	// 4a. Run requests through the metrics middleware plus the
+	// --middleware/INVOK_MIDDLEWARE selection (logging/recovery/
+	// request-ID/CORS by default) before they reach the router; add
+	// your own middleware to namedMiddleware the same way.
+	srv.Handler = srv.TrackInFlight(middleware.Chain(r,
+		append([]middleware.Middleware{metrics.Middleware}, selectMiddleware(*middlewareList)...)...,
+	))
+
+	// 5. Create a net.Listener to have more control over incoming
+	// connections. A listener inherited from a parent via --reload
+	// takes priority; otherwise bind fresh, with SO_REUSEPORT when
+	// --reload is set so the next process can bind before this one
+	// exits.
+	var listener net.Listener
+	inherited, inheritedOK, err := reload.InheritedListener()
 	if err != nil {
-		log.Fatalf("Error starting listener: %v", err)
+		log.Fatalf("Error inheriting listener: %v", err)
+	}
+	switch {
+	case inheritedOK:
+		listener = inherited
+	case *reloadMode:
+		listener, err = reload.ListenReusePort("tcp", ":"+port)
+		if err != nil {
+			log.Printf("Falling back to a regular bind: %v", err)
+			listener, err = net.Listen("tcp", ":"+port)
+			if err != nil {
+				log.Fatalf("Error starting listener: %v", err)
+			}
+		}
+	default:
+		listener, err = net.Listen("tcp", ":"+port)
+		if err != nil {
+			log.Fatalf("Error starting listener: %v", err)
+		}
+	}
+
+	// 5a. Now that the socket is bound, open the browser before
+	// entering Serve so it never races an un-bound listener.
+	if *open {
+		url := fmt.Sprintf("http://localhost:%s/{{ROUTE}}", port)
+		if err := browser.OpenURL(url); err != nil {
+			log.Printf("Could not open browser: %v", err)
+		}
 	}
 
 	// 6. Start the server in a separate goroutine.
@@ -50,27 +166,51 @@ func main() {
 		}
 	}()
 
-	// 7. Set up channel to receive signal notifications.
+	// 7. Set up channels to receive signal notifications: SIGINT/SIGTERM
+	// for shutdown, SIGHUP for a --reload handoff.
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
 
-	// 8. Block until a signal is received.
-	<-stop
-	log.Println("Shutting down the server...")
-
-	// 9. Stop accepting new connections immediately by closing the listener.
-	if err := listener.Close(); err != nil {
-		log.Printf("Error closing listener: %v", err)
+	// 8. Block until a signal arrives or /admin/shutdown is called. The
+	// reload handoff reuses the same drain path below: once the child
+	// has the listener, this process just finishes its own requests.
+	select {
+	case <-stop:
+		log.Println("Shutting down the server (signal received)...")
+	case <-srv.ShutdownChan:
+		log.Println("Shutting down the server (admin request received)...")
+	case <-hup:
+		log.Println("Reload requested, handing off listener...")
+		tcpListener, ok := listener.(*net.TCPListener)
+		if !ok || !*reloadMode {
+			log.Println("Listener does not support --reload handoff, shutting down instead")
+			break
+		}
+		if err := reload.Reexec(tcpListener); err != nil {
+			log.Printf("Error re-executing for reload: %v", err)
+		} else {
+			log.Println("New process started, draining this one...")
+		}
 	}
 
-	// 10. Create a context with a timeout to allow active requests to finish.
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// 8a. Fail /readyz immediately so a load balancer stops routing new
+	// traffic here while the in-flight requests below finish.
+	healthReg.Drain()
+
+	// 9. Create a context with a timeout to allow active requests to
+	// finish, then attempt a graceful shutdown. Shutdown closes the
+	// listener itself; closing it here ourselves first would make
+	// Serve's goroutine above observe a "closed network connection"
+	// error instead of http.ErrServerClosed and fatal out before this
+	// drain even runs.
+	ctx, cancel := context.WithTimeout(context.Background(), srv.DrainTimeout)
 	defer cancel()
 
-	// 11. Attempt a graceful shutdown.
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
 	log.Println("Server exited gracefully.")
-}
\ No newline at end of file
+}
@@ -0,0 +1,12 @@
+//go:build !typed
+
+package main
+
+import "github.com/gorilla/mux"
+
+// registerRoute wires "/{{ROUTE}}" to {{HANDLER}} for every method.
+// Pass --method/--request/--response to invok to scope a generated
+// handler to a single HTTP method instead.
+func registerRoute(r *mux.Router) {
+	r.HandleFunc("/{{ROUTE}}", {{HANDLER}})
+}
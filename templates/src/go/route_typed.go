@@ -0,0 +1,17 @@
+//go:build typed
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// registerRoute wires "/{{ROUTE}}" to {{HANDLER}}, scoped to
+// {{METHOD}}. It is the only route registration main makes for
+// "/{{ROUTE}}", so requests with any other method are not routed to
+// {{HANDLER}}.
+func registerRoute(r *mux.Router) {
+	r.HandleFunc("/{{ROUTE}}", {{HANDLER}}).Methods(http.Method{{METHOD}})
+}